@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/zafer1337/Evax/detector"
+	"github.com/zafer1337/Evax/eventlog"
+)
+
+// Anomaly is an identified anomaly, tagged with the detector rule that
+// raised it plus enough metadata for a Notifier to label it.
+type Anomaly struct {
+	LogID       string `json:"log_id"`
+	Rule        string `json:"rule"`
+	Severity    string `json:"severity"`
+	Channel     string `json:"channel"`
+	EventID     int    `json:"event_id"`
+	Description string `json:"description"`
+}
+
+// authFailureDetector is the original Security-channel keyword check,
+// expressed as a detector.Detector.
+type authFailureDetector struct{}
+
+func (authFailureDetector) Name() string     { return "auth-failure" }
+func (authFailureDetector) Severity() string { return "medium" }
+
+func (authFailureDetector) Match(entry eventlog.LogEntry) (bool, string) {
+	details := strings.ToLower(entry.Details)
+	switch {
+	case strings.Contains(details, "failed login"):
+		return true, "failed login detected"
+	case strings.Contains(details, "account locked"):
+		return true, "account lockout detected"
+	default:
+		return false, ""
+	}
+}
+
+// defaultDetectors returns the detector set used when no YAML rules file is
+// configured: the built-in auth-failure rule plus the Sysmon pack.
+func defaultDetectors() []detector.Detector {
+	dets := []detector.Detector{authFailureDetector{}}
+	return append(dets, detector.SysmonDetectors()...)
+}
+
+// defaultEventLogConfigs returns one eventlog.Config per channel
+// defaultDetectors covers, so the Sysmon pack gets Sysmon events to match.
+func defaultEventLogConfigs() []eventlog.Config {
+	return []eventlog.Config{
+		eventlog.DefaultConfig(),
+		{EventChannel: detector.SysmonChannel, EventIDs: detector.SysmonEventIDs()},
+	}
+}
+
+// analyzeLogs runs pipeline over logs and converts its findings into
+// Anomalies for escalation.
+func analyzeLogs(logs []eventlog.LogEntry, pipeline *detector.Pipeline) []Anomaly {
+	findings := pipeline.Run(logs)
+	anomalies := make([]Anomaly, len(findings))
+	for i, f := range findings {
+		anomalies[i] = Anomaly{
+			LogID:       f.LogID,
+			Rule:        f.Rule,
+			Severity:    f.Severity,
+			Channel:     f.Channel,
+			EventID:     f.EventID,
+			Description: fmt.Sprintf("[%s/%s] %s", f.Rule, f.Severity, f.Description),
+		}
+	}
+	return anomalies
+}
@@ -0,0 +1,67 @@
+// Package detector analyzes LogEntry values for anomalies using a pluggable
+// pipeline of Detectors, each tagging its findings with a rule and severity.
+package detector
+
+import "github.com/zafer1337/Evax/eventlog"
+
+// Detector decides whether a single LogEntry is anomalous and, if so, why.
+type Detector interface {
+	// Name identifies the rule for tagging findings and for log/metric labels.
+	Name() string
+	// Severity classifies how urgent a match from this Detector is (e.g.
+	// "low", "medium", "high", "critical").
+	Severity() string
+	// Match reports whether entry matches this rule and, if so, a
+	// human-readable reason.
+	Match(entry eventlog.LogEntry) (bool, string)
+}
+
+// Finding is an anomaly raised by one Detector in a Pipeline.
+type Finding struct {
+	LogID       string
+	Rule        string
+	Severity    string
+	Channel     string
+	EventID     int
+	Description string
+}
+
+// Pipeline runs a fixed set of Detectors over LogEntry values.
+type Pipeline struct {
+	detectors []Detector
+}
+
+// NewPipeline builds a Pipeline that runs each of detectors in order.
+func NewPipeline(detectors ...Detector) *Pipeline {
+	return &Pipeline{detectors: detectors}
+}
+
+// Run evaluates every Detector against every entry and returns all matches.
+func (p *Pipeline) Run(entries []eventlog.LogEntry) []Finding {
+	var findings []Finding
+	for _, entry := range entries {
+		findings = append(findings, p.RunOne(entry)...)
+	}
+	return findings
+}
+
+// RunOne evaluates every Detector against a single entry; it's what the
+// daemon's streaming path uses so it doesn't need to buffer a batch.
+func (p *Pipeline) RunOne(entry eventlog.LogEntry) []Finding {
+	var findings []Finding
+	for _, d := range p.detectors {
+		matched, reason := d.Match(entry)
+		if !matched {
+			continue
+		}
+		findings = append(findings, Finding{
+			LogID:       entry.ID,
+			Rule:        d.Name(),
+			Severity:    d.Severity(),
+			Channel:     entry.Channel,
+			EventID:     entry.EventID,
+			Description: reason,
+		})
+	}
+	return findings
+}
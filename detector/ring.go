@@ -0,0 +1,58 @@
+package detector
+
+import (
+	"sync"
+	"time"
+)
+
+// slidingRing tracks event timestamps per key within a rolling time window,
+// for threshold rules like "N 4625s from the same source IP within 5m". It's
+// in-memory only; a restart resetting the count is an acceptable tradeoff.
+type slidingRing struct {
+	mu     sync.Mutex
+	window time.Duration
+	events map[string][]time.Time
+}
+
+func newSlidingRing(window time.Duration) *slidingRing {
+	return &slidingRing{
+		window: window,
+		events: make(map[string][]time.Time),
+	}
+}
+
+// add records an event for key at now and returns how many events for key
+// remain within the trailing window, including this one. It also sweeps
+// every other key's events, deleting any whose entries have all fallen
+// outside the window, so keys that stop recurring (e.g. a source IP seen
+// only once) don't accumulate forever in a long-running process.
+func (r *slidingRing) add(key string, now time.Time) int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	cutoff := now.Add(-r.window)
+	for k, times := range r.events {
+		if k == key {
+			continue
+		}
+		if times = trim(times, cutoff); len(times) == 0 {
+			delete(r.events, k)
+		} else {
+			r.events[k] = times
+		}
+	}
+
+	times := trim(append(r.events[key], now), cutoff)
+	r.events[key] = times
+	return len(times)
+}
+
+// trim drops the leading entries of times that fall before cutoff, returning
+// the remaining (already time-ordered) suffix.
+func trim(times []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(times) && times[i].Before(cutoff) {
+		i++
+	}
+	return times[i:]
+}
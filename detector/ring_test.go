@@ -0,0 +1,64 @@
+package detector
+
+import (
+	"testing"
+	"time"
+)
+
+func TestSlidingRingCountsWithinWindow(t *testing.T) {
+	r := newSlidingRing(5 * time.Minute)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	if got := r.add("1.2.3.4", base); got != 1 {
+		t.Errorf("first add = %d, want 1", got)
+	}
+	if got := r.add("1.2.3.4", base.Add(time.Minute)); got != 2 {
+		t.Errorf("second add = %d, want 2", got)
+	}
+	if got := r.add("1.2.3.4", base.Add(2*time.Minute)); got != 3 {
+		t.Errorf("third add = %d, want 3", got)
+	}
+}
+
+func TestSlidingRingEvictsOutsideWindow(t *testing.T) {
+	r := newSlidingRing(5 * time.Minute)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r.add("1.2.3.4", base)
+	r.add("1.2.3.4", base.Add(time.Minute))
+
+	if got := r.add("1.2.3.4", base.Add(10*time.Minute)); got != 1 {
+		t.Errorf("add after window elapsed = %d, want 1", got)
+	}
+}
+
+func TestSlidingRingTracksKeysIndependently(t *testing.T) {
+	r := newSlidingRing(5 * time.Minute)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r.add("1.2.3.4", base)
+	if got := r.add("5.6.7.8", base); got != 1 {
+		t.Errorf("add for different key = %d, want 1", got)
+	}
+}
+
+func TestSlidingRingDeletesStaleKeysInsteadOfLeakingThem(t *testing.T) {
+	r := newSlidingRing(5 * time.Minute)
+	base := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	r.add("1.2.3.4", base)
+	if len(r.events) != 1 {
+		t.Fatalf("len(r.events) = %d, want 1 after one key's first event", len(r.events))
+	}
+
+	// A second key's event, long after the first key's window has elapsed,
+	// should sweep the first key's stale entry out of the map rather than
+	// leaving it behind forever.
+	r.add("5.6.7.8", base.Add(10*time.Minute))
+	if len(r.events) != 1 {
+		t.Errorf("len(r.events) = %d, want 1 (stale key swept on unrelated add)", len(r.events))
+	}
+	if _, ok := r.events["1.2.3.4"]; ok {
+		t.Error("expected stale key 1.2.3.4 to be deleted, not left as an empty entry")
+	}
+}
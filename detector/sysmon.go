@@ -0,0 +1,61 @@
+package detector
+
+import (
+	"fmt"
+
+	"github.com/zafer1337/Evax/eventlog"
+)
+
+// SysmonChannel is the channel Sysmon logs to once installed.
+const SysmonChannel = "Microsoft-Windows-Sysmon/Operational"
+
+// sysmonRule pairs a commonly-useful Sysmon event ID with a rule name and
+// default severity.
+type sysmonRule struct {
+	eventID  int
+	name     string
+	severity string
+}
+
+// sysmonRules covers the handful of Sysmon event IDs most security content
+// builds on: process creation, network connections, file creation, and DNS
+// queries.
+var sysmonRules = []sysmonRule{
+	{1, "sysmon-process-create", "low"},
+	{3, "sysmon-network-connect", "medium"},
+	{11, "sysmon-file-create", "low"},
+	{22, "sysmon-dns-query", "low"},
+}
+
+// SysmonDetectors returns one Detector per commonly-useful Sysmon event ID.
+func SysmonDetectors() []Detector {
+	out := make([]Detector, len(sysmonRules))
+	for i, r := range sysmonRules {
+		out[i] = sysmonDetector{r}
+	}
+	return out
+}
+
+// SysmonEventIDs returns the event IDs SysmonDetectors matches on, for
+// callers that need to scope an eventlog.Config to just those events.
+func SysmonEventIDs() []int {
+	ids := make([]int, len(sysmonRules))
+	for i, r := range sysmonRules {
+		ids[i] = r.eventID
+	}
+	return ids
+}
+
+type sysmonDetector struct {
+	rule sysmonRule
+}
+
+func (d sysmonDetector) Name() string     { return d.rule.name }
+func (d sysmonDetector) Severity() string { return d.rule.severity }
+
+func (d sysmonDetector) Match(entry eventlog.LogEntry) (bool, string) {
+	if entry.Channel != SysmonChannel || entry.EventID != d.rule.eventID {
+		return false, ""
+	}
+	return true, fmt.Sprintf("Sysmon event ID %d on %s", d.rule.eventID, entry.Channel)
+}
@@ -0,0 +1,196 @@
+package detector
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/zafer1337/Evax/eventlog"
+)
+
+// ThresholdConfig configures brute-force style detection: a rule fires once
+// Count or more matching events sharing GroupBy's value arrive within
+// Window, e.g. "5 failed logons from the same source IP within 5 minutes".
+type ThresholdConfig struct {
+	Count   int           `yaml:"count"`
+	Window  time.Duration `yaml:"window"`
+	GroupBy string        `yaml:"group_by"` // EventData field name, e.g. "IpAddress"
+}
+
+// RuleConfig is one entry in a YAML rules file. Match filters on Channel and
+// EventIDs but does not use XPath; EventLogConfigs reads Channel, EventIDs,
+// and XPath to drive the fetch side so a rules file can also pull from
+// channels other than the defaults. Level is read by neither.
+type RuleConfig struct {
+	Name      string           `yaml:"name"`
+	Channel   string           `yaml:"channel"`
+	EventIDs  []int            `yaml:"event_ids"`
+	Level     string           `yaml:"level"`
+	XPath     string           `yaml:"xpath"`
+	Keywords  []string         `yaml:"keywords"`
+	Regex     string           `yaml:"regex"`
+	Severity  string           `yaml:"severity"`
+	Threshold *ThresholdConfig `yaml:"threshold"`
+}
+
+type rulesFile struct {
+	Rules []RuleConfig `yaml:"rules"`
+}
+
+// LoadYAMLRules reads rule definitions from path and compiles each into a
+// YAMLRuleDetector.
+func LoadYAMLRules(path string) ([]Detector, error) {
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read rules file: %w", err)
+	}
+
+	var rf rulesFile
+	if err := yaml.Unmarshal(raw, &rf); err != nil {
+		return nil, fmt.Errorf("failed to parse rules file: %w", err)
+	}
+
+	detectors := make([]Detector, 0, len(rf.Rules))
+	for _, rc := range rf.Rules {
+		d, err := newYAMLRuleDetector(rc)
+		if err != nil {
+			return nil, fmt.Errorf("rule %q: %w", rc.Name, err)
+		}
+		detectors = append(detectors, d)
+	}
+	return detectors, nil
+}
+
+// EventLogConfigs derives one eventlog.Config per distinct channel that the
+// YAMLRuleDetectors in rules target, so main can fetch those channels
+// instead of only the built-in defaults. Rules with no channel set are
+// skipped, since they run against whatever is already fetched.
+func EventLogConfigs(rules []Detector) []eventlog.Config {
+	var configs []eventlog.Config
+	seen := make(map[string]int) // channel -> index into configs
+
+	for _, r := range rules {
+		yr, ok := r.(*YAMLRuleDetector)
+		if !ok || yr.cfg.Channel == "" {
+			continue
+		}
+
+		i, ok := seen[yr.cfg.Channel]
+		if !ok {
+			i = len(configs)
+			seen[yr.cfg.Channel] = i
+			configs = append(configs, eventlog.Config{EventChannel: yr.cfg.Channel})
+		}
+
+		switch {
+		case yr.cfg.XPath != "":
+			configs[i].XPathQuery = yr.cfg.XPath
+		case configs[i].XPathQuery == "":
+			configs[i].EventIDs = append(configs[i].EventIDs, yr.cfg.EventIDs...)
+		}
+	}
+	return configs
+}
+
+// YAMLRuleDetector matches LogEntry values against a single RuleConfig's
+// filters, keyword/regex match, and optional sliding-window threshold.
+type YAMLRuleDetector struct {
+	cfg   RuleConfig
+	regex *regexp.Regexp
+	ring  *slidingRing // nil unless cfg.Threshold is set
+}
+
+func newYAMLRuleDetector(cfg RuleConfig) (*YAMLRuleDetector, error) {
+	d := &YAMLRuleDetector{cfg: cfg}
+
+	if cfg.Regex != "" {
+		re, err := regexp.Compile(cfg.Regex)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regex: %w", err)
+		}
+		d.regex = re
+	}
+
+	if cfg.Threshold != nil {
+		if cfg.Threshold.Count <= 0 || cfg.Threshold.Window <= 0 {
+			return nil, fmt.Errorf("threshold requires count > 0 and window > 0")
+		}
+		d.ring = newSlidingRing(cfg.Threshold.Window)
+	}
+
+	return d, nil
+}
+
+// Config returns the RuleConfig d was built from, for callers outside this
+// package that need its fetch-side fields.
+func (d *YAMLRuleDetector) Config() RuleConfig { return d.cfg }
+
+// Name returns the rule's configured name.
+func (d *YAMLRuleDetector) Name() string { return d.cfg.Name }
+
+// Severity returns the rule's configured severity, defaulting to "medium".
+func (d *YAMLRuleDetector) Severity() string {
+	if d.cfg.Severity == "" {
+		return "medium"
+	}
+	return d.cfg.Severity
+}
+
+// Match applies the rule's filters, then (if configured) a sliding-window
+// threshold, before reporting an anomaly.
+func (d *YAMLRuleDetector) Match(entry eventlog.LogEntry) (bool, string) {
+	if d.cfg.Channel != "" && !strings.EqualFold(entry.Channel, d.cfg.Channel) {
+		return false, ""
+	}
+	if len(d.cfg.EventIDs) > 0 && !containsInt(d.cfg.EventIDs, entry.EventID) {
+		return false, ""
+	}
+
+	if !d.contentMatches(entry) {
+		return false, ""
+	}
+
+	if d.ring == nil {
+		return true, fmt.Sprintf("matched rule %q", d.cfg.Name)
+	}
+
+	key := entry.Data[d.cfg.Threshold.GroupBy]
+	count := d.ring.add(key, time.Now())
+	if count < d.cfg.Threshold.Count {
+		return false, ""
+	}
+	return true, fmt.Sprintf("%d matching events from %q=%q within %s (rule %q)",
+		count, d.cfg.Threshold.GroupBy, key, d.cfg.Threshold.Window, d.cfg.Name)
+}
+
+// contentMatches reports whether entry.Details satisfies the rule's keyword
+// and/or regex filters. A rule with neither always matches.
+func (d *YAMLRuleDetector) contentMatches(entry eventlog.LogEntry) bool {
+	if len(d.cfg.Keywords) == 0 && d.regex == nil {
+		return true
+	}
+
+	details := strings.ToLower(entry.Details)
+	for _, kw := range d.cfg.Keywords {
+		if strings.Contains(details, strings.ToLower(kw)) {
+			return true
+		}
+	}
+	if d.regex != nil && d.regex.MatchString(entry.Details) {
+		return true
+	}
+	return false
+}
+
+func containsInt(ids []int, id int) bool {
+	for _, want := range ids {
+		if want == id {
+			return true
+		}
+	}
+	return false
+}
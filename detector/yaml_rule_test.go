@@ -0,0 +1,192 @@
+package detector
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/zafer1337/Evax/eventlog"
+)
+
+func TestYAMLRuleDetectorChannelAndEventIDFilter(t *testing.T) {
+	d, err := newYAMLRuleDetector(RuleConfig{
+		Name:     "test-rule",
+		Channel:  "Security",
+		EventIDs: []int{4625},
+	})
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector: %v", err)
+	}
+
+	matched, _ := d.Match(eventlog.LogEntry{Channel: "Security", EventID: 4625})
+	if !matched {
+		t.Error("expected match on channel+event ID")
+	}
+
+	matched, _ = d.Match(eventlog.LogEntry{Channel: "Application", EventID: 4625})
+	if matched {
+		t.Error("expected no match on wrong channel")
+	}
+
+	matched, _ = d.Match(eventlog.LogEntry{Channel: "Security", EventID: 1})
+	if matched {
+		t.Error("expected no match on wrong event ID")
+	}
+}
+
+func TestYAMLRuleDetectorKeywordMatch(t *testing.T) {
+	d, err := newYAMLRuleDetector(RuleConfig{
+		Name:     "test-rule",
+		Keywords: []string{"Failed Login"},
+	})
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector: %v", err)
+	}
+
+	matched, _ := d.Match(eventlog.LogEntry{Details: "a failed login occurred"})
+	if !matched {
+		t.Error("expected case-insensitive keyword match")
+	}
+
+	matched, _ = d.Match(eventlog.LogEntry{Details: "nothing interesting"})
+	if matched {
+		t.Error("expected no match without keyword")
+	}
+}
+
+func TestYAMLRuleDetectorRegexMatch(t *testing.T) {
+	d, err := newYAMLRuleDetector(RuleConfig{
+		Name:  "test-rule",
+		Regex: `user=\w+`,
+	})
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector: %v", err)
+	}
+
+	matched, _ := d.Match(eventlog.LogEntry{Details: "login for user=alice"})
+	if !matched {
+		t.Error("expected regex match")
+	}
+
+	matched, _ = d.Match(eventlog.LogEntry{Details: "no user field here"})
+	if matched {
+		t.Error("expected no regex match")
+	}
+}
+
+func TestYAMLRuleDetectorNoContentFilterMatchesAnything(t *testing.T) {
+	d, err := newYAMLRuleDetector(RuleConfig{Name: "test-rule", Channel: "Security"})
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector: %v", err)
+	}
+
+	matched, _ := d.Match(eventlog.LogEntry{Channel: "Security", Details: "anything at all"})
+	if !matched {
+		t.Error("expected match when rule has no keyword/regex filter")
+	}
+}
+
+func TestYAMLRuleDetectorThreshold(t *testing.T) {
+	d, err := newYAMLRuleDetector(RuleConfig{
+		Name: "brute-force",
+		Threshold: &ThresholdConfig{
+			Count:   3,
+			Window:  5 * time.Minute,
+			GroupBy: "IpAddress",
+		},
+	})
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector: %v", err)
+	}
+
+	entry := func(ip string) eventlog.LogEntry {
+		return eventlog.LogEntry{Data: map[string]string{"IpAddress": ip}}
+	}
+
+	if matched, _ := d.Match(entry("1.2.3.4")); matched {
+		t.Error("1st match: expected no anomaly below threshold")
+	}
+	if matched, _ := d.Match(entry("1.2.3.4")); matched {
+		t.Error("2nd match: expected no anomaly below threshold")
+	}
+	matched, reason := d.Match(entry("1.2.3.4"))
+	if !matched {
+		t.Error("3rd match: expected anomaly once threshold is reached")
+	}
+	if reason == "" {
+		t.Error("expected a non-empty reason once threshold is reached")
+	}
+}
+
+func TestYAMLRuleDetectorThresholdRequiresPositiveCountAndWindow(t *testing.T) {
+	if _, err := newYAMLRuleDetector(RuleConfig{Name: "bad", Threshold: &ThresholdConfig{Count: 0, Window: 1}}); err == nil {
+		t.Error("expected error for threshold with count <= 0")
+	}
+	if _, err := newYAMLRuleDetector(RuleConfig{Name: "bad", Threshold: &ThresholdConfig{Count: 1, Window: 0}}); err == nil {
+		t.Error("expected error for threshold with window <= 0")
+	}
+}
+
+func TestYAMLRuleDetectorInvalidRegex(t *testing.T) {
+	if _, err := newYAMLRuleDetector(RuleConfig{Name: "bad", Regex: "(unclosed"}); err == nil {
+		t.Error("expected error for invalid regex")
+	}
+}
+
+func TestLoadYAMLRules(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "rules.yaml")
+	contents := `
+rules:
+  - name: test-rule
+    channel: Security
+    event_ids: [4625]
+    severity: high
+`
+	if err := os.WriteFile(path, []byte(contents), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	dets, err := LoadYAMLRules(path)
+	if err != nil {
+		t.Fatalf("LoadYAMLRules: %v", err)
+	}
+	if len(dets) != 1 {
+		t.Fatalf("LoadYAMLRules returned %d detectors, want 1", len(dets))
+	}
+	if dets[0].Name() != "test-rule" {
+		t.Errorf("Name() = %q, want %q", dets[0].Name(), "test-rule")
+	}
+	if dets[0].Severity() != "high" {
+		t.Errorf("Severity() = %q, want %q", dets[0].Severity(), "high")
+	}
+}
+
+func TestEventLogConfigsOnePerChannel(t *testing.T) {
+	rules := []Detector{
+		mustYAMLRuleDetector(t, RuleConfig{Name: "r1", Channel: "Security", EventIDs: []int{4625}}),
+		mustYAMLRuleDetector(t, RuleConfig{Name: "r2", Channel: "Microsoft-Windows-PowerShell/Operational", XPath: "*[System[(EventID=4104)]]"}),
+		mustYAMLRuleDetector(t, RuleConfig{Name: "r3", Channel: ""}), // no channel: skipped
+	}
+
+	configs := EventLogConfigs(rules)
+	if len(configs) != 2 {
+		t.Fatalf("EventLogConfigs returned %d configs, want 2", len(configs))
+	}
+
+	if configs[0].EventChannel != "Security" || len(configs[0].EventIDs) != 1 || configs[0].EventIDs[0] != 4625 {
+		t.Errorf("configs[0] = %+v, want Security/[4625]", configs[0])
+	}
+	if configs[1].EventChannel != "Microsoft-Windows-PowerShell/Operational" || configs[1].XPathQuery != "*[System[(EventID=4104)]]" {
+		t.Errorf("configs[1] = %+v, want PowerShell channel with the rule's XPath", configs[1])
+	}
+}
+
+func mustYAMLRuleDetector(t *testing.T, cfg RuleConfig) *YAMLRuleDetector {
+	t.Helper()
+	d, err := newYAMLRuleDetector(cfg)
+	if err != nil {
+		t.Fatalf("newYAMLRuleDetector(%+v): %v", cfg, err)
+	}
+	return d
+}
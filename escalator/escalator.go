@@ -0,0 +1,23 @@
+// Package escalator turns raw anomalies into analyst-readable explanations
+// via a pluggable backend: OpenAI, a rule-based fallback, or a no-op.
+package escalator
+
+import "context"
+
+// Anomaly is the minimal shape an Escalator needs to explain a finding,
+// decoupled from the caller's own anomaly/log types.
+type Anomaly struct {
+	LogID       string
+	Description string
+}
+
+// Explanation is the analyst-facing result of escalating an Anomaly.
+type Explanation struct {
+	LogID   string
+	Summary string
+}
+
+// Escalator explains a batch of anomalies.
+type Escalator interface {
+	Explain(ctx context.Context, anomalies []Anomaly) ([]Explanation, error)
+}
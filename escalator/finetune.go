@@ -0,0 +1,66 @@
+package escalator
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	openai "github.com/sashabaranov/go-openai"
+)
+
+// FineTuneCmd implements the `finetune` subcommand: it uploads a JSONL file
+// of past anomalies plus analyst labels and kicks off a fine-tuning job, so
+// operators can specialize the OpenAI model on their own logs.
+func FineTuneCmd(args []string) error {
+	fs := flag.NewFlagSet("finetune", flag.ExitOnError)
+	trainingFile := fs.String("training-file", "", "path to a JSONL file of {messages: [...]} fine-tuning examples")
+	baseModel := fs.String("base-model", defaultModel, "base model to fine-tune")
+	suffix := fs.String("suffix", "evax", "suffix appended to the resulting fine-tuned model's name")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *trainingFile == "" {
+		return fmt.Errorf("finetune: -training-file is required")
+	}
+
+	key := apiKey()
+	if key == "" {
+		return fmt.Errorf("finetune: no OpenAI API key configured; set OPENAI_API_KEY or add openai_api_key to the config file")
+	}
+	client := openai.NewClient(key)
+	ctx := context.Background()
+
+	f, err := os.Open(*trainingFile)
+	if err != nil {
+		return fmt.Errorf("finetune: failed to open training file: %w", err)
+	}
+	defer f.Close()
+
+	data, err := io.ReadAll(f)
+	if err != nil {
+		return fmt.Errorf("finetune: failed to read training file: %w", err)
+	}
+
+	uploaded, err := client.CreateFileBytes(ctx, openai.FileBytesRequest{
+		Name:    *trainingFile,
+		Bytes:   data,
+		Purpose: openai.PurposeFineTune,
+	})
+	if err != nil {
+		return fmt.Errorf("finetune: failed to upload training file: %w", err)
+	}
+
+	job, err := client.CreateFineTuningJob(ctx, openai.FineTuningJobRequest{
+		TrainingFile: uploaded.ID,
+		Model:        *baseModel,
+		Suffix:       *suffix,
+	})
+	if err != nil {
+		return fmt.Errorf("finetune: failed to create fine-tuning job: %w", err)
+	}
+
+	fmt.Printf("Created fine-tuning job %s (status: %s)\n", job.ID, job.Status)
+	return nil
+}
@@ -0,0 +1,44 @@
+package escalator
+
+import (
+	"context"
+	"strings"
+)
+
+// localRule maps a keyword found in an anomaly's description to a canned,
+// analyst-readable explanation. Checked in order; the first match wins.
+type localRule struct {
+	keyword     string
+	explanation string
+}
+
+var localRules = []localRule{
+	{"account locked", "Repeated failed logons triggered an account lockout policy; verify whether this is a legitimate user or a brute-force attempt against the account."},
+	{"failed login", "A logon attempt failed authentication; check the source host and account for a pattern of repeated failures indicating credential guessing."},
+}
+
+// LocalRules is an Escalator that explains anomalies using a small set of
+// keyword-matched canned explanations, entirely offline.
+type LocalRules struct{}
+
+// genericExplanation is used for anomalies that match no localRule, so
+// LocalRules never silently drops an anomaly.
+const genericExplanation = "Anomaly detected; no canned explanation matched it. Review the description and source log for details."
+
+// Explain matches each anomaly's description against localRules, falling
+// back to genericExplanation for anomalies that match no rule.
+func (LocalRules) Explain(ctx context.Context, anomalies []Anomaly) ([]Explanation, error) {
+	out := make([]Explanation, len(anomalies))
+	for i, a := range anomalies {
+		desc := strings.ToLower(a.Description)
+		summary := genericExplanation
+		for _, rule := range localRules {
+			if strings.Contains(desc, rule.keyword) {
+				summary = rule.explanation
+				break
+			}
+		}
+		out[i] = Explanation{LogID: a.LogID, Summary: summary}
+	}
+	return out, nil
+}
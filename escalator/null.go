@@ -0,0 +1,12 @@
+package escalator
+
+import "context"
+
+// Null is an Escalator that explains nothing, for running with escalation
+// disabled instead of special-casing a nil Escalator everywhere.
+type Null struct{}
+
+// Explain always returns no explanations and no error.
+func (Null) Explain(ctx context.Context, anomalies []Anomaly) ([]Explanation, error) {
+	return nil, nil
+}
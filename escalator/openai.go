@@ -0,0 +1,229 @@
+package escalator
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"math/rand"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"golang.org/x/time/rate"
+)
+
+const (
+	defaultModel        = "gpt-4o-mini"
+	defaultBatchSize    = 10
+	defaultMaxTokens    = 500
+	defaultRPM          = 60
+	maxRetries          = 5
+	defaultSystemPrompt = "You are a SOC analyst assistant. Given a batch of security anomalies, return a concise, one-to-two sentence explanation for each, referencing the log_id it corresponds to."
+)
+
+// OpenAI is an Escalator backed by OpenAI's Chat Completions API, with the
+// key read from OPENAI_API_KEY or a config file at construction time.
+type OpenAI struct {
+	client    *openai.Client
+	model     string
+	maxTokens int
+	batchSize int
+	limiter   *rate.Limiter
+}
+
+// OpenAIOption configures an OpenAI escalator; see With* functions below.
+type OpenAIOption func(*OpenAI)
+
+// WithModel overrides the default chat model (gpt-4o-mini).
+func WithModel(model string) OpenAIOption { return func(o *OpenAI) { o.model = model } }
+
+// WithMaxTokens overrides the default response token budget.
+func WithMaxTokens(n int) OpenAIOption { return func(o *OpenAI) { o.maxTokens = n } }
+
+// WithBatchSize overrides how many anomalies are explained per request.
+func WithBatchSize(n int) OpenAIOption { return func(o *OpenAI) { o.batchSize = n } }
+
+// WithRateLimit overrides the requests-per-minute cap applied to outgoing calls.
+func WithRateLimit(rpm int) OpenAIOption {
+	return func(o *OpenAI) { o.limiter = rate.NewLimiter(rate.Limit(float64(rpm)/60.0), 1) }
+}
+
+// NewOpenAI constructs an OpenAI escalator, returning an error rather than
+// panicking when no API key is configured.
+func NewOpenAI(opts ...OpenAIOption) (*OpenAI, error) {
+	key := apiKey()
+	if key == "" {
+		return nil, errors.New("no OpenAI API key configured: set OPENAI_API_KEY or add openai_api_key to the Evax config file")
+	}
+
+	o := &OpenAI{
+		client:    openai.NewClient(key),
+		model:     defaultModel,
+		maxTokens: defaultMaxTokens,
+		batchSize: defaultBatchSize,
+		limiter:   rate.NewLimiter(rate.Limit(defaultRPM)/60.0, 1),
+	}
+	for _, opt := range opts {
+		opt(o)
+	}
+	return o, nil
+}
+
+// apiKey resolves the OpenAI key from the environment first, then the
+// config file, so it's never a literal in source.
+func apiKey() string {
+	if key := os.Getenv("OPENAI_API_KEY"); key != "" {
+		return key
+	}
+	raw, err := os.ReadFile(configFilePath())
+	if err != nil {
+		return ""
+	}
+	var cfg struct {
+		OpenAIAPIKey string `json:"openai_api_key"`
+	}
+	if err := json.Unmarshal(raw, &cfg); err != nil {
+		return ""
+	}
+	return cfg.OpenAIAPIKey
+}
+
+// configFilePath returns the Evax config file path, overridable via
+// EVAX_CONFIG for tests and non-default deployments.
+func configFilePath() string {
+	if path := os.Getenv("EVAX_CONFIG"); path != "" {
+		return path
+	}
+	return "evax.config.json"
+}
+
+type explanationItem struct {
+	LogID   string `json:"log_id"`
+	Summary string `json:"summary"`
+}
+
+type explanationBatch struct {
+	Explanations []explanationItem `json:"explanations"`
+}
+
+// responseSchema constrains the model to return explanations keyed by
+// log_id, parseable directly instead of scraped out of free-form prose.
+var responseSchema = json.RawMessage(`{
+	"type": "object",
+	"properties": {
+		"explanations": {
+			"type": "array",
+			"items": {
+				"type": "object",
+				"properties": {
+					"log_id": {"type": "string"},
+					"summary": {"type": "string"}
+				},
+				"required": ["log_id", "summary"]
+			}
+		}
+	},
+	"required": ["explanations"]
+}`)
+
+// Explain batches anomalies into groups of o.batchSize and asks OpenAI to
+// explain each group, honoring rate limits and retrying transient failures.
+func (o *OpenAI) Explain(ctx context.Context, anomalies []Anomaly) ([]Explanation, error) {
+	var out []Explanation
+	for start := 0; start < len(anomalies); start += o.batchSize {
+		end := start + o.batchSize
+		if end > len(anomalies) {
+			end = len(anomalies)
+		}
+		batch, err := o.explainBatch(ctx, anomalies[start:end])
+		if err != nil {
+			return out, err
+		}
+		out = append(out, batch...)
+	}
+	return out, nil
+}
+
+func (o *OpenAI) explainBatch(ctx context.Context, anomalies []Anomaly) ([]Explanation, error) {
+	var userContent strings.Builder
+	userContent.WriteString("Explain each of the following anomalies:\n")
+	for _, a := range anomalies {
+		fmt.Fprintf(&userContent, "- log_id=%s: %s\n", a.LogID, a.Description)
+	}
+
+	req := openai.ChatCompletionRequest{
+		Model:     o.model,
+		MaxTokens: o.maxTokens,
+		Messages: []openai.ChatCompletionMessage{
+			{Role: openai.ChatMessageRoleSystem, Content: defaultSystemPrompt},
+			{Role: openai.ChatMessageRoleUser, Content: userContent.String()},
+		},
+		ResponseFormat: &openai.ChatCompletionResponseFormat{
+			Type: openai.ChatCompletionResponseFormatTypeJSONSchema,
+			JSONSchema: &openai.ChatCompletionResponseFormatJSONSchema{
+				Name:   "anomaly_explanations",
+				Schema: responseSchema,
+				Strict: true,
+			},
+		},
+	}
+
+	var resp openai.ChatCompletionResponse
+	err := o.withRetry(ctx, func() error {
+		if err := o.limiter.Wait(ctx); err != nil {
+			return err
+		}
+		var apiErr error
+		resp, apiErr = o.client.CreateChatCompletion(ctx, req)
+		return apiErr
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get response from OpenAI: %w", err)
+	}
+	if len(resp.Choices) == 0 {
+		return nil, errors.New("OpenAI returned no choices")
+	}
+
+	var batch explanationBatch
+	if err := json.Unmarshal([]byte(resp.Choices[0].Message.Content), &batch); err != nil {
+		return nil, fmt.Errorf("failed to parse OpenAI response: %w", err)
+	}
+
+	out := make([]Explanation, len(batch.Explanations))
+	for i, item := range batch.Explanations {
+		out[i] = Explanation{LogID: item.LogID, Summary: item.Summary}
+	}
+	return out, nil
+}
+
+// withRetry retries fn on 429/5xx responses with exponential backoff plus
+// jitter. go-openai's APIError doesn't surface the HTTP Retry-After header,
+// so there's no server guidance to honor here.
+func (o *OpenAI) withRetry(ctx context.Context, fn func() error) error {
+	var lastErr error
+	for attempt := 0; attempt < maxRetries; attempt++ {
+		lastErr = fn()
+		if lastErr == nil {
+			return nil
+		}
+
+		var apiErr *openai.APIError
+		if !errors.As(lastErr, &apiErr) || (apiErr.HTTPStatusCode != http.StatusTooManyRequests && apiErr.HTTPStatusCode < 500) {
+			return lastErr
+		}
+
+		backoff := time.Duration(math.Pow(2, float64(attempt))) * 500 * time.Millisecond
+		wait := backoff + time.Duration(rand.Int63n(int64(backoff)/2+1))
+
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+	return lastErr
+}
@@ -0,0 +1,156 @@
+package escalator
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	openai "github.com/sashabaranov/go-openai"
+	"golang.org/x/time/rate"
+)
+
+// newTestOpenAI builds an OpenAI escalator pointed at server instead of the
+// real API, bypassing NewOpenAI's API-key resolution.
+func newTestOpenAI(server *httptest.Server, batchSize int) *OpenAI {
+	config := openai.DefaultConfig("test-key")
+	config.BaseURL = server.URL + "/v1"
+	return &OpenAI{
+		client:    openai.NewClientWithConfig(config),
+		model:     defaultModel,
+		maxTokens: defaultMaxTokens,
+		batchSize: batchSize,
+		limiter:   rate.NewLimiter(rate.Inf, 1),
+	}
+}
+
+func chatCompletionResponse(explanations []explanationItem) string {
+	body, _ := json.Marshal(explanationBatch{Explanations: explanations})
+	resp := openai.ChatCompletionResponse{
+		Choices: []openai.ChatCompletionChoice{
+			{Message: openai.ChatCompletionMessage{Content: string(body)}},
+		},
+	}
+	raw, _ := json.Marshal(resp)
+	return string(raw)
+}
+
+func TestExplainBatchesAnomaliesByBatchSize(t *testing.T) {
+	var batchSizes []int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req openai.ChatCompletionRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Fatalf("decode request: %v", err)
+		}
+		userMsg := req.Messages[len(req.Messages)-1].Content
+		count := strings.Count(userMsg, "log_id=")
+		batchSizes = append(batchSizes, count)
+
+		explanations := make([]explanationItem, count)
+		for i := range explanations {
+			explanations[i] = explanationItem{LogID: fmt.Sprintf("id-%d", i), Summary: "ok"}
+		}
+		fmt.Fprint(w, chatCompletionResponse(explanations))
+	}))
+	defer server.Close()
+
+	o := newTestOpenAI(server, 2)
+	anomalies := make([]Anomaly, 5)
+	for i := range anomalies {
+		anomalies[i] = Anomaly{LogID: fmt.Sprintf("id-%d", i), Description: "desc"}
+	}
+
+	out, err := o.Explain(context.Background(), anomalies)
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if len(out) != 5 {
+		t.Errorf("Explain returned %d explanations, want 5", len(out))
+	}
+	want := []int{2, 2, 1}
+	if len(batchSizes) != len(want) {
+		t.Fatalf("got %d requests, want %d", len(batchSizes), len(want))
+	}
+	for i, w := range want {
+		if batchSizes[i] != w {
+			t.Errorf("request %d had %d anomalies, want %d", i, batchSizes[i], w)
+		}
+	}
+}
+
+func TestWithRetryRetriesOnServerErrorThenSucceeds(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			w.WriteHeader(http.StatusInternalServerError)
+			fmt.Fprint(w, `{"error": {"message": "internal error", "type": "server_error"}}`)
+			return
+		}
+		fmt.Fprint(w, chatCompletionResponse([]explanationItem{{LogID: "id-0", Summary: "ok"}}))
+	}))
+	defer server.Close()
+
+	o := newTestOpenAI(server, 10)
+	out, err := o.Explain(context.Background(), []Anomaly{{LogID: "id-0", Description: "desc"}})
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if len(out) != 1 {
+		t.Fatalf("Explain returned %d explanations, want 1", len(out))
+	}
+	if got := atomic.LoadInt32(&attempts); got != 3 {
+		t.Errorf("server saw %d attempts, want 3 (2 failures + 1 success)", got)
+	}
+}
+
+func TestWithRetryGivesUpOnNonRetryableStatus(t *testing.T) {
+	var attempts int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&attempts, 1)
+		w.WriteHeader(http.StatusBadRequest)
+		fmt.Fprint(w, `{"error": {"message": "bad request", "type": "invalid_request_error"}}`)
+	}))
+	defer server.Close()
+
+	o := newTestOpenAI(server, 10)
+	_, err := o.Explain(context.Background(), []Anomaly{{LogID: "id-0", Description: "desc"}})
+	if err == nil {
+		t.Fatal("expected Explain to return an error for a 400 response")
+	}
+	if got := atomic.LoadInt32(&attempts); got != 1 {
+		t.Errorf("server saw %d attempts, want 1 (no retry on 400)", got)
+	}
+}
+
+func TestWithRetryBackoffGrowsExponentiallyWithJitter(t *testing.T) {
+	o := &OpenAI{}
+	apiErr := func() error { return &openai.APIError{HTTPStatusCode: http.StatusTooManyRequests} }
+
+	var attempts int32
+	start := time.Now()
+	err := o.withRetry(context.Background(), func() error {
+		if atomic.AddInt32(&attempts, 1) <= 2 {
+			return apiErr()
+		}
+		return nil
+	})
+	elapsed := time.Since(start)
+	if err != nil {
+		t.Fatalf("withRetry: %v", err)
+	}
+
+	// Two retries: backoff(attempt=0) is in [500ms, 750ms], backoff(attempt=1)
+	// is in [1000ms, 1500ms], so the elapsed time should be at least 1.5s but
+	// well under maxRetries*the largest possible backoff.
+	if elapsed < 1500*time.Millisecond {
+		t.Errorf("withRetry returned after %s, want at least 1.5s of backoff", elapsed)
+	}
+	if elapsed > 5*time.Second {
+		t.Errorf("withRetry returned after %s, want well under 5s", elapsed)
+	}
+}
@@ -0,0 +1,22 @@
+//go:build !windows
+
+package eventlog
+
+import (
+	"context"
+	"errors"
+)
+
+// errWindowsOnly is returned by Fetch and Subscribe on platforms other than
+// Windows, which have no Windows Event Log to query.
+var errWindowsOnly = errors.New("eventlog: Windows Event Log access requires GOOS=windows")
+
+// Fetch always fails on non-Windows platforms; see eventlog_windows.go.
+func Fetch(cfg Config) ([]LogEntry, error) {
+	return nil, errWindowsOnly
+}
+
+// Subscribe always fails on non-Windows platforms; see eventlog_windows.go.
+func Subscribe(ctx context.Context, cfg Config, out chan<- LogEntry) error {
+	return errWindowsOnly
+}
@@ -0,0 +1,85 @@
+package eventlog
+
+import (
+	"testing"
+	"time"
+)
+
+func TestResolveChannelDefaultsToSecurity(t *testing.T) {
+	if got := resolveChannel(Config{}); got != "Security" {
+		t.Errorf("resolveChannel(zero Config) = %q, want %q", got, "Security")
+	}
+	if got := resolveChannel(Config{EventChannel: "Application"}); got != "Application" {
+		t.Errorf("resolveChannel = %q, want %q", got, "Application")
+	}
+}
+
+func TestResolveXPathPrefersExplicitQuery(t *testing.T) {
+	cfg := Config{EventIDs: []int{4625}, XPathQuery: "*[System[(EventID=1)]]"}
+	if got := resolveXPath(cfg); got != cfg.XPathQuery {
+		t.Errorf("resolveXPath = %q, want explicit XPathQuery %q", got, cfg.XPathQuery)
+	}
+}
+
+func TestBuildXPathQuery(t *testing.T) {
+	tests := []struct {
+		name string
+		cfg  Config
+		want string
+	}{
+		{
+			name: "no filters matches everything",
+			cfg:  Config{},
+			want: "*",
+		},
+		{
+			name: "single event ID",
+			cfg:  Config{EventIDs: []int{4625}},
+			want: "*[System[(EventID=4625)]]",
+		},
+		{
+			name: "multiple event IDs are ORed",
+			cfg:  Config{EventIDs: []int{1, 3, 11}},
+			want: "*[System[(EventID=1 or EventID=3 or EventID=11)]]",
+		},
+		{
+			name: "event ID and level are ANDed",
+			cfg:  Config{EventIDs: []int{4625}, EventLevel: LevelError},
+			want: "*[System[(EventID=4625) and (Level=2)]]",
+		},
+		{
+			name: "since record ID",
+			cfg:  Config{EventIDs: []int{4625}, SinceRecordID: 100},
+			want: "*[System[(EventID=4625) and (EventRecordID>100)]]",
+		},
+		{
+			name: "since duration takes precedence over since record ID",
+			cfg:  Config{EventIDs: []int{4625}, SinceRecordID: 100, SinceDuration: 24 * time.Hour},
+			want: "*[System[(EventID=4625) and TimeCreated[timediff(@SystemTime) <= 86400000]]]",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := buildXPathQuery(tt.cfg); got != tt.want {
+				t.Errorf("buildXPathQuery(%+v) = %q, want %q", tt.cfg, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMaxRecordID(t *testing.T) {
+	if got := MaxRecordID(nil); got != 0 {
+		t.Errorf("MaxRecordID(nil) = %d, want 0", got)
+	}
+
+	logs := []LogEntry{
+		{ID: "10"},
+		{ID: "42"},
+		{ID: "not-a-number"},
+		{ID: "7"},
+	}
+	if got := MaxRecordID(logs); got != 42 {
+		t.Errorf("MaxRecordID = %d, want 42", got)
+	}
+}
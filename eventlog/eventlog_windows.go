@@ -0,0 +1,213 @@
+//go:build windows
+
+package eventlog
+
+import (
+	"context"
+	"encoding/xml"
+	"fmt"
+	"strconv"
+	"syscall"
+
+	"github.com/google/winops/winlog"
+	"github.com/google/winops/winlog/wevtapi"
+	"golang.org/x/sys/windows"
+)
+
+// localeEnUS is the LCID GetRenderedEvents formats messages in.
+const localeEnUS = 1033
+
+// fetchBatchSize is how many events EvtNext/GetRenderedEvents are asked to
+// return per call.
+const fetchBatchSize = 64
+
+// subscribePollInterval is how long Subscribe waits on the subscription's
+// signal event between checks of ctx.
+const subscribePollInterval = 1000 // milliseconds
+
+// eventXML is the subset of the rendered event XML we care about: System
+// metadata, EventData fields, and the RenderingInfo message.
+type eventXML struct {
+	System struct {
+		Provider struct {
+			Name string `xml:"Name,attr"`
+		} `xml:"Provider"`
+		EventID       string `xml:"EventID"`
+		EventRecordID string `xml:"EventRecordID"`
+		Channel       string `xml:"Channel"`
+		TimeCreated   struct {
+			SystemTime string `xml:"SystemTime,attr"`
+		} `xml:"TimeCreated"`
+		Computer string `xml:"Computer"`
+		Task     string `xml:"Task"`
+	} `xml:"System"`
+	EventData struct {
+		Data []struct {
+			Name  string `xml:"Name,attr"`
+			Value string `xml:",chardata"`
+		} `xml:"Data"`
+	} `xml:"EventData"`
+	RenderingInfo struct {
+		Message string `xml:"Message"`
+	} `xml:"RenderingInfo"`
+}
+
+// parseEventXML parses a rendered event (as returned by
+// winlog.GetRenderedEvents) into the LogEntry shape used throughout Evax;
+// both the one-shot query path and the push subscription path share it.
+func parseEventXML(rendered string) (LogEntry, error) {
+	var ev eventXML
+	if err := xml.Unmarshal([]byte(rendered), &ev); err != nil {
+		return LogEntry{}, fmt.Errorf("failed to parse event XML: %w", err)
+	}
+
+	eventID, _ := strconv.Atoi(ev.System.EventID)
+
+	var data map[string]string
+	if len(ev.EventData.Data) > 0 {
+		data = make(map[string]string, len(ev.EventData.Data))
+		for _, d := range ev.EventData.Data {
+			data[d.Name] = d.Value
+		}
+	}
+
+	return LogEntry{
+		ID:        ev.System.EventRecordID,
+		EventID:   eventID,
+		Channel:   ev.System.Channel,
+		Timestamp: ev.System.TimeCreated.SystemTime,
+		EventType: ev.System.Task,
+		Details:   ev.RenderingInfo.Message,
+		Data:      data,
+	}, nil
+}
+
+// closePublisherCache releases the publisher metadata handles
+// GetRenderedEvents accumulates in cache.
+func closePublisherCache(cache map[string]windows.Handle) {
+	for _, h := range cache {
+		winlog.Close(h)
+	}
+}
+
+// Fetch queries the Windows Event Log via EvtQuery/EvtNext for events
+// matching cfg and returns them as LogEntry values.
+func Fetch(cfg Config) ([]LogEntry, error) {
+	channelPtr, err := syscall.UTF16PtrFromString(resolveChannel(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode channel: %w", err)
+	}
+	queryPtr, err := syscall.UTF16PtrFromString(resolveXPath(cfg))
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	handle, err := wevtapi.EvtQuery(0, channelPtr, queryPtr, wevtapi.EvtQueryChannelPath|wevtapi.EvtQueryForwardDirection)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query event log: %w", err)
+	}
+	defer winlog.Close(handle)
+
+	publisherCache := make(map[string]windows.Handle)
+	defer closePublisherCache(publisherCache)
+
+	var logs []LogEntry
+	for {
+		rendered, err := winlog.GetRenderedEvents(&winlog.SubscribeConfig{}, publisherCache, handle, fetchBatchSize, localeEnUS)
+		if err == windows.ERROR_NO_MORE_ITEMS {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch next events: %w", err)
+		}
+		if len(rendered) == 0 {
+			break
+		}
+		for _, raw := range rendered {
+			entry, err := parseEventXML(raw)
+			if err != nil {
+				return nil, err
+			}
+			logs = append(logs, entry)
+		}
+	}
+
+	return logs, nil
+}
+
+// Subscribe opens a live EvtSubscribe push subscription on cfg's
+// channel/filter and streams matching events to out as LogEntry values
+// until ctx is canceled, at which point it closes the subscription and out.
+func Subscribe(ctx context.Context, cfg Config, out chan<- LogEntry) error {
+	channelPtr, err := syscall.UTF16PtrFromString(resolveChannel(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to encode channel: %w", err)
+	}
+	queryPtr, err := syscall.UTF16PtrFromString(resolveXPath(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to encode query: %w", err)
+	}
+
+	signalEvent, err := windows.CreateEvent(nil, 1, 0, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create subscription signal: %w", err)
+	}
+
+	subCfg := &winlog.SubscribeConfig{
+		SignalEvent: signalEvent,
+		ChannelPath: channelPtr,
+		Query:       queryPtr,
+		Flags:       wevtapi.EvtSubscribeToFutureEvents,
+	}
+
+	subscription, err := winlog.Subscribe(subCfg)
+	if err != nil {
+		subCfg.Close()
+		return fmt.Errorf("failed to subscribe to event log: %w", err)
+	}
+	defer winlog.Close(subscription)
+	defer subCfg.Close()
+
+	publisherCache := make(map[string]windows.Handle)
+	defer closePublisherCache(publisherCache)
+
+	for {
+		select {
+		case <-ctx.Done():
+			close(out)
+			return nil
+		default:
+		}
+
+		status, err := windows.WaitForSingleObject(subCfg.SignalEvent, subscribePollInterval)
+		if err != nil {
+			return fmt.Errorf("failed waiting for new events: %w", err)
+		}
+		if status != windows.WAIT_OBJECT_0 {
+			continue
+		}
+
+		rendered, err := winlog.GetRenderedEvents(subCfg, publisherCache, subscription, fetchBatchSize, localeEnUS)
+		if err == windows.ERROR_NO_MORE_ITEMS || err == windows.ERROR_INVALID_OPERATION {
+			windows.ResetEvent(subCfg.SignalEvent)
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("failed to fetch subscribed events: %w", err)
+		}
+
+		for _, raw := range rendered {
+			entry, err := parseEventXML(raw)
+			if err != nil {
+				return err
+			}
+			select {
+			case out <- entry:
+			case <-ctx.Done():
+				close(out)
+				return nil
+			}
+		}
+		windows.ResetEvent(subCfg.SignalEvent)
+	}
+}
@@ -2,141 +2,242 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
-	"os/exec"
+	"os"
+	"os/signal"
+	"strconv"
 	"strings"
+	"syscall"
+	"time"
 
-	"github.com/go-toast/toast"
-	openai "github.com/sashabaranov/go-openai"
+	"github.com/zafer1337/Evax/detector"
+	"github.com/zafer1337/Evax/escalator"
+	"github.com/zafer1337/Evax/eventlog"
+	"github.com/zafer1337/Evax/notifier"
 )
 
-// LogEntry represents a single log entry fetched from Windows Event Viewer.
-type LogEntry struct {
-	ID        string `json:"id"`
-	Timestamp string `json:"timestamp"`
-	EventType string `json:"event_type"`
-	Details   string `json:"details"`
-}
-
-// Anomaly represents an identified anomaly in the logs.
-type Anomaly struct {
-	LogID       string `json:"log_id"`
-	Description string `json:"description"`
-}
-
-// fetchWindowsEventLogs fetches security logs from Windows Event Viewer using `wevtutil`.
-func fetchWindowsEventLogs() ([]LogEntry, error) {
-	cmd := exec.Command("wevtutil", "qe", "Security", "/q:*[System[(EventID=4625)]]", "/f:Text")
-	output, err := cmd.Output()
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch logs: %w", err)
+func main() {
+	if len(os.Args) > 1 && os.Args[1] == "finetune" {
+		if err := escalator.FineTuneCmd(os.Args[2:]); err != nil {
+			log.Fatalf("finetune: %v", err)
+		}
+		return
 	}
-	return parseWindowsLogs(string(output)), nil
-}
 
-// parseWindowsLogs parses the raw log data into a structured format.
-func parseWindowsLogs(rawLogs string) []LogEntry {
-	lines := strings.Split(rawLogs, "\n")
-	var logs []LogEntry
-	var current LogEntry
-	for _, line := range lines {
-		line = strings.TrimSpace(line)
-		if strings.HasPrefix(line, "Event ID:") {
-			current = LogEntry{}
-			current.ID = strings.TrimPrefix(line, "Event ID: ")
-		} else if strings.HasPrefix(line, "Time Created:") {
-			current.Timestamp = strings.TrimPrefix(line, "Time Created: ")
-		} else if strings.HasPrefix(line, "Task:") {
-			current.EventType = strings.TrimPrefix(line, "Task: ")
-		} else if strings.HasPrefix(line, "Message:") {
-			current.Details = strings.TrimPrefix(line, "Message: ")
-			logs = append(logs, current)
+	cfg := defaultRunConfig()
+
+	var daemon bool
+	var since time.Duration
+	var escalatorName string
+	var openAIModel string
+	var openAIMaxTokens int
+	var openAIBatchSize int
+	var rulesFile string
+	var notifierNames string
+	var webhookURL string
+	var slackWebhookURL string
+	var metricsAddr string
+	var eventIDs string
+	var level string
+	flag.BoolVar(&daemon, "daemon", false, "run continuously, subscribing to new events until terminated")
+	flag.DurationVar(&since, "since", 0, "one-shot audit of events from the last duration (e.g. 24h), ignoring saved positions")
+	flag.StringVar(&cfg.EventLog.EventChannel, "channel", cfg.EventLog.EventChannel, "Windows Event Log channel to query (overriding this drops the additional Sysmon-channel scan defaultRunConfig enables by default, and resets -event-ids unless also set explicitly)")
+	flag.StringVar(&eventIDs, "event-ids", "", "comma-separated event IDs to match on -channel (default: 4625 on the default Security channel; none, i.e. every event, when -channel is set)")
+	flag.StringVar(&level, "level", "", "Windows Event Log level to match on -channel: critical, error, warning, information, or verbose")
+	flag.StringVar(&cfg.PositionsFile, "positions", cfg.PositionsFile, "path to the positions file tracking last-seen EventRecordID per channel")
+	flag.IntVar(&cfg.Workers, "workers", cfg.Workers, "number of workers processing events in daemon mode")
+	flag.DurationVar(&cfg.ShutdownTimeout, "shutdown-timeout", cfg.ShutdownTimeout, "how long to wait for in-flight anomalies to drain on shutdown")
+	flag.StringVar(&escalatorName, "escalator", "openai", "escalation backend: openai, rules, or null")
+	flag.StringVar(&openAIModel, "openai-model", "", "OpenAI chat model to use (default gpt-4o-mini)")
+	flag.IntVar(&openAIMaxTokens, "openai-max-tokens", 0, "max response tokens per OpenAI request")
+	flag.IntVar(&openAIBatchSize, "openai-batch-size", 0, "max anomalies explained per OpenAI request")
+	flag.StringVar(&rulesFile, "rules", "", "path to a YAML rules file; when set, its rules replace the built-in auth-failure detector (the Sysmon detectors and Sysmon-channel scan stay on)")
+	flag.StringVar(&notifierNames, "notifiers", "toast", "comma-separated notification sinks: toast, webhook, slack, prometheus")
+	flag.StringVar(&webhookURL, "webhook-url", "", "URL the webhook notifier POSTs anomalies to")
+	flag.StringVar(&slackWebhookURL, "slack-webhook-url", "", "Slack incoming webhook URL for the slack notifier")
+	flag.StringVar(&metricsAddr, "metrics-addr", ":9090", "address the prometheus notifier serves /metrics on")
+	flag.Parse()
+
+	cfg.Daemon = daemon
+	cfg.Since = since
+
+	var eventIDsSet bool
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "channel":
+			cfg.EventLogs = nil
+		case "event-ids":
+			eventIDsSet = true
 		}
+	})
+	if eventIDsSet {
+		ids, err := parseEventIDs(eventIDs)
+		if err != nil {
+			log.Fatalf("Error parsing -event-ids: %v", err)
+		}
+		cfg.EventLog.EventIDs = ids
+	} else if cfg.EventLogs == nil {
+		// -channel was set without -event-ids: the default 4625 no longer
+		// means anything on an arbitrary channel, so match every event on it.
+		cfg.EventLog.EventIDs = nil
+	}
+	if level != "" {
+		lvl, err := parseEventLevel(level)
+		if err != nil {
+			log.Fatalf("Error parsing -level: %v", err)
+		}
+		cfg.EventLog.EventLevel = lvl
 	}
-	return logs
-}
 
-// analyzeLogs detects anomalies in the logs using rule-based logic.
-func analyzeLogs(logs []LogEntry) []Anomaly {
-	anomalies := []Anomaly{}
-	for _, log := range logs {
-		if isAnomalous(log) {
-			anomalies = append(anomalies, Anomaly{
-				LogID:       log.ID,
-				Description: fmt.Sprintf("Potential anomaly detected in log with ID %s: %s", log.ID, log.Details),
+	if rulesFile != "" {
+		rules, err := detector.LoadYAMLRules(rulesFile)
+		if err != nil {
+			log.Fatalf("Error loading rules file: %v", err)
+		}
+		cfg.Pipeline = detector.NewPipeline(append(rules, detector.SysmonDetectors()...)...)
+		cfg.EventLogs = append(cfg.EventLogs, detector.EventLogConfigs(rules)...)
+		if !hasChannel(cfg, detector.SysmonChannel) {
+			cfg.EventLogs = append(cfg.EventLogs, eventlog.Config{
+				EventChannel: detector.SysmonChannel,
+				EventIDs:     detector.SysmonEventIDs(),
 			})
 		}
 	}
-	return anomalies
-}
 
-// isAnomalous checks if a log entry is anomalous using embedded rules.
-func isAnomalous(log LogEntry) bool {
-	return strings.Contains(strings.ToLower(log.Details), "failed login") ||
-		strings.Contains(strings.ToLower(log.Details), "account locked")
-}
+	esc, err := buildEscalator(escalatorName, openAIModel, openAIMaxTokens, openAIBatchSize)
+	if err != nil {
+		log.Fatalf("Error configuring escalator: %v", err)
+	}
+	cfg.Escalator = esc
 
-// escalateToOpenAI sends the anomaly description to OpenAI for further analysis.
-func escalateToOpenAI(anomaly Anomaly) (string, error) {
-	client := openai.NewClient("OPENAIKEY")
-	ctx := context.Background()
+	// On Windows the Go runtime delivers CTRL_CLOSE_EVENT (and the logoff/
+	// shutdown events) as syscall.SIGTERM, so this also covers clean shutdown
+	// when the console window is closed, in addition to SIGINT/SIGTERM.
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
-	prompt := fmt.Sprintf("Provide a concise explanation for the following anomaly:\n%s", anomaly.Description)
-	resp, err := client.CreateCompletion(ctx, openai.CompletionRequest{
-		Model:     "text-davinci-003",
-		Prompt:    prompt,
-		MaxTokens: 50,
-	})
+	backends, prom, err := buildNotifiers(notifierNames, webhookURL, slackWebhookURL)
 	if err != nil {
-		return "", fmt.Errorf("failed to get response from OpenAI: %w", err)
+		log.Fatalf("Error configuring notifiers: %v", err)
+	}
+	cfg.Notifier = backends
+
+	if prom != nil {
+		go func() {
+			if err := prom.Serve(ctx, metricsAddr); err != nil {
+				log.Printf("Prometheus metrics server stopped: %v", err)
+			}
+		}()
 	}
 
-	return strings.TrimSpace(resp.Choices[0].Text), nil
+	if err := Run(ctx, cfg); err != nil {
+		log.Fatalf("Evax exited with error: %v", err)
+	}
 }
 
-// sendWindowsNotification sends a Windows notification with the provided message.
-func sendWindowsNotification(title, message string) {
-	notification := toast.Notification{
-		AppID:   "Windows Security Audit",
-		Title:   title,
-		Message: message,
-		Icon:    "", // Optional: Path to an icon file.
+// hasChannel reports whether cfg already fetches channel, via cfg.EventLog
+// or any entry in cfg.EventLogs.
+func hasChannel(cfg Config, channel string) bool {
+	if cfg.EventLog.EventChannel == channel {
+		return true
 	}
-	if err := notification.Push(); err != nil {
-		log.Printf("Failed to send notification: %v", err)
+	for _, c := range cfg.EventLogs {
+		if c.EventChannel == channel {
+			return true
+		}
 	}
+	return false
 }
 
-func main() {
-	// Step 1: Fetch Windows Event Logs
-	logs, err := fetchWindowsEventLogs()
-	if err != nil {
-		log.Fatalf("Error fetching logs: %v", err)
-	}
-	fmt.Printf("Fetched %d logs.\n", len(logs))
-
-	// Step 2: Analyze logs for anomalies
-	anomalies := analyzeLogs(logs)
-	if len(anomalies) == 0 {
-		message := "No anomalies detected. Your system is safe."
-		fmt.Println(message)
-		sendWindowsNotification("Security Audit", message)
-		return
+// parseEventIDs parses -event-ids' comma-separated list into ints.
+func parseEventIDs(s string) ([]int, error) {
+	fields := strings.Split(s, ",")
+	ids := make([]int, 0, len(fields))
+	for _, f := range fields {
+		id, err := strconv.Atoi(strings.TrimSpace(f))
+		if err != nil {
+			return nil, fmt.Errorf("invalid event ID %q: %w", f, err)
+		}
+		ids = append(ids, id)
 	}
+	return ids, nil
+}
 
-	// Step 3: Handle anomalies
-	for _, anomaly := range anomalies {
-		fmt.Printf("Anomaly detected: %s\n", anomaly.Description)
+// parseEventLevel maps -level's value to an eventlog.EventLevel.
+func parseEventLevel(s string) (eventlog.EventLevel, error) {
+	switch strings.ToLower(s) {
+	case "critical":
+		return eventlog.LevelCritical, nil
+	case "error":
+		return eventlog.LevelError, nil
+	case "warning":
+		return eventlog.LevelWarning, nil
+	case "information":
+		return eventlog.LevelInformation, nil
+	case "verbose":
+		return eventlog.LevelVerbose, nil
+	default:
+		return "", fmt.Errorf("unknown level %q (want critical, error, warning, information, or verbose)", s)
+	}
+}
 
-		// Escalate anomaly to OpenAI for analysis
-		openAIResponse, err := escalateToOpenAI(anomaly)
-		if err != nil {
-			log.Printf("Failed to get OpenAI response for anomaly %s: %v", anomaly.LogID, err)
-			continue
+// buildNotifiers constructs the fan-out Notifier selected by -notifiers,
+// also returning the *notifier.Prometheus instance (if configured) so main
+// can start its /metrics server.
+func buildNotifiers(names, webhookURL, slackWebhookURL string) (notifier.Multi, *notifier.Prometheus, error) {
+	var backends notifier.Multi
+	var prom *notifier.Prometheus
+
+	for _, name := range strings.Split(names, ",") {
+		switch strings.TrimSpace(name) {
+		case "toast":
+			backends = append(backends, notifier.Toast{})
+		case "webhook":
+			if webhookURL == "" {
+				return nil, nil, fmt.Errorf("notifier %q requires -webhook-url", name)
+			}
+			backends = append(backends, notifier.Webhook{URL: webhookURL})
+		case "slack":
+			if slackWebhookURL == "" {
+				return nil, nil, fmt.Errorf("notifier %q requires -slack-webhook-url", name)
+			}
+			backends = append(backends, notifier.Slack{WebhookURL: slackWebhookURL})
+		case "prometheus":
+			prom = notifier.NewPrometheus()
+			backends = append(backends, prom)
+		case "":
+			// allow trailing commas / empty -notifiers without erroring
+		default:
+			return nil, nil, fmt.Errorf("unknown notifier %q (want toast, webhook, slack, or prometheus)", name)
 		}
+	}
 
-		// Send a notification with OpenAI's response
-		sendWindowsNotification("Security Audit - Anomaly Detected", openAIResponse)
+	return backends, prom, nil
+}
+
+// buildEscalator constructs the Escalator selected by -escalator, applying
+// any OpenAI-specific overrides that were set.
+func buildEscalator(name, model string, maxTokens, batchSize int) (escalator.Escalator, error) {
+	switch name {
+	case "null":
+		return escalator.Null{}, nil
+	case "rules":
+		return escalator.LocalRules{}, nil
+	case "openai":
+		var opts []escalator.OpenAIOption
+		if model != "" {
+			opts = append(opts, escalator.WithModel(model))
+		}
+		if maxTokens > 0 {
+			opts = append(opts, escalator.WithMaxTokens(maxTokens))
+		}
+		if batchSize > 0 {
+			opts = append(opts, escalator.WithBatchSize(batchSize))
+		}
+		return escalator.NewOpenAI(opts...)
+	default:
+		return nil, fmt.Errorf("unknown escalator %q (want openai, rules, or null)", name)
 	}
 }
@@ -0,0 +1,40 @@
+// Package notifier delivers anomaly notifications to pluggable sinks: a
+// Windows toast, a generic webhook, Slack, or Prometheus metrics.
+package notifier
+
+import (
+	"context"
+	"errors"
+)
+
+// Notification is what a Notifier delivers: enough about a single anomaly
+// to render a human-facing message and to tag a metric.
+type Notification struct {
+	Title    string
+	Message  string
+	LogID    string
+	Rule     string
+	Severity string
+	Channel  string
+	EventID  int
+}
+
+// Notifier delivers a Notification to some sink.
+type Notifier interface {
+	Notify(ctx context.Context, n Notification) error
+}
+
+// Multi fans a Notification out to every Notifier in the slice, continuing
+// past individual failures and returning their combined error.
+type Multi []Notifier
+
+// Notify implements Notifier by calling Notify on every configured backend.
+func (m Multi) Notify(ctx context.Context, n Notification) error {
+	var errs []error
+	for _, backend := range m {
+		if err := backend.Notify(ctx, n); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
@@ -0,0 +1,173 @@
+package notifier
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/prometheus/client_golang/prometheus/testutil"
+)
+
+type stubNotifier struct {
+	err error
+}
+
+func (s stubNotifier) Notify(ctx context.Context, n Notification) error { return s.err }
+
+func TestMultiNotifyCallsEveryBackend(t *testing.T) {
+	var called []int
+	record := func(i int) Notifier {
+		return stubNotifierFunc(func(ctx context.Context, n Notification) error {
+			called = append(called, i)
+			return nil
+		})
+	}
+	m := Multi{record(1), record(2), record(3)}
+
+	if err := m.Notify(context.Background(), Notification{}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if len(called) != 3 {
+		t.Errorf("called %v backends, want all 3", called)
+	}
+}
+
+func TestMultiNotifyContinuesPastFailuresAndJoinsErrors(t *testing.T) {
+	errA := errors.New("backend A failed")
+	errC := errors.New("backend C failed")
+	var bCalled bool
+	m := Multi{
+		stubNotifier{err: errA},
+		stubNotifierFunc(func(ctx context.Context, n Notification) error { bCalled = true; return nil }),
+		stubNotifier{err: errC},
+	}
+
+	err := m.Notify(context.Background(), Notification{})
+	if !bCalled {
+		t.Error("expected backend B to run despite backend A's failure")
+	}
+	if !errors.Is(err, errA) || !errors.Is(err, errC) {
+		t.Errorf("Notify error = %v, want it to wrap both backend errors", err)
+	}
+}
+
+type stubNotifierFunc func(ctx context.Context, n Notification) error
+
+func (f stubNotifierFunc) Notify(ctx context.Context, n Notification) error { return f(ctx, n) }
+
+func TestWebhookNotifyPostsJSON(t *testing.T) {
+	var gotMethod, gotContentType string
+	var gotBody Notification
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		gotMethod = r.Method
+		gotContentType = r.Header.Get("Content-Type")
+		if err := json.NewDecoder(r.Body).Decode(&gotBody); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	w := Webhook{URL: server.URL}
+	n := Notification{Title: "t", Message: "m", Rule: "r", Severity: "high", Channel: "Security", EventID: 4625}
+	if err := w.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if gotMethod != http.MethodPost {
+		t.Errorf("method = %q, want POST", gotMethod)
+	}
+	if gotContentType != "application/json" {
+		t.Errorf("Content-Type = %q, want application/json", gotContentType)
+	}
+	if gotBody != n {
+		t.Errorf("posted body = %+v, want %+v", gotBody, n)
+	}
+}
+
+func TestWebhookNotifyErrorsOnNonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	w := Webhook{URL: server.URL}
+	if err := w.Notify(context.Background(), Notification{}); err == nil {
+		t.Error("expected Notify to error on a 500 response")
+	}
+}
+
+func TestSlackNotifyPostsBlockKitPayload(t *testing.T) {
+	var gotPayload slackPayload
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if err := json.NewDecoder(r.Body).Decode(&gotPayload); err != nil {
+			t.Fatalf("decode request body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	s := Slack{WebhookURL: server.URL}
+	n := Notification{Title: "Brute force", Message: "too many logons", Rule: "brute-force", Severity: "high", Channel: "Security", EventID: 4625}
+	if err := s.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if len(gotPayload.Blocks) != 2 {
+		t.Fatalf("got %d blocks, want 2", len(gotPayload.Blocks))
+	}
+	section := gotPayload.Blocks[0]
+	if section.Type != "section" || section.Text == nil || !strings.Contains(section.Text.Text, n.Title) || !strings.Contains(section.Text.Text, n.Message) {
+		t.Errorf("section block = %+v, want it to contain title %q and message %q", section, n.Title, n.Message)
+	}
+	context := gotPayload.Blocks[1]
+	if context.Type != "context" || len(context.Elements) != 1 || !strings.Contains(context.Elements[0].Text, n.Rule) {
+		t.Errorf("context block = %+v, want it to contain rule %q", context, n.Rule)
+	}
+}
+
+func TestSlackNotifyErrorsOnNonSuccessStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+	}))
+	defer server.Close()
+
+	s := Slack{WebhookURL: server.URL}
+	if err := s.Notify(context.Background(), Notification{}); err == nil {
+		t.Error("expected Notify to error on a 400 response")
+	}
+}
+
+func TestPrometheusNotifyIncrementsAnomaliesTotal(t *testing.T) {
+	p := NewPrometheus()
+	n := Notification{Rule: "brute-force", Channel: "Security", EventID: 4625}
+
+	if err := p.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+	if err := p.Notify(context.Background(), n); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	got := testutil.ToFloat64(p.anomaliesTotal.WithLabelValues(n.Rule, n.Channel, "4625"))
+	if got != 2 {
+		t.Errorf("evax_anomalies_total = %v, want 2", got)
+	}
+}
+
+func TestPrometheusNotifySetsLastScanSeconds(t *testing.T) {
+	p := NewPrometheus()
+	if before := testutil.ToFloat64(p.lastScanSeconds); before != 0 {
+		t.Fatalf("lastScanSeconds before Notify = %v, want 0", before)
+	}
+
+	if err := p.Notify(context.Background(), Notification{}); err != nil {
+		t.Fatalf("Notify: %v", err)
+	}
+
+	if after := testutil.ToFloat64(p.lastScanSeconds); after == 0 {
+		t.Error("lastScanSeconds after Notify = 0, want a nonzero Unix timestamp")
+	}
+}
@@ -0,0 +1,78 @@
+package notifier
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+// Prometheus doesn't deliver anomalies anywhere itself; it records them as
+// metrics on its own CollectorRegistry for an existing monitoring stack to
+// scrape via Serve's /metrics endpoint.
+type Prometheus struct {
+	registry        *prometheus.Registry
+	anomaliesTotal  *prometheus.CounterVec
+	lastScanSeconds prometheus.Gauge
+}
+
+// NewPrometheus builds a Prometheus notifier with its own registry, so it
+// doesn't collide with metrics the host process registers elsewhere.
+func NewPrometheus() *Prometheus {
+	registry := prometheus.NewRegistry()
+
+	anomaliesTotal := prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "evax_anomalies_total",
+		Help: "Total anomalies detected, by rule, channel, and event ID.",
+	}, []string{"rule", "channel", "event_id"})
+
+	lastScanSeconds := prometheus.NewGauge(prometheus.GaugeOpts{
+		Name: "evax_last_scan_timestamp_seconds",
+		Help: "Unix timestamp of the most recently delivered notification, including a no-anomalies notice.",
+	})
+
+	registry.MustRegister(anomaliesTotal, lastScanSeconds)
+
+	return &Prometheus{
+		registry:        registry,
+		anomaliesTotal:  anomaliesTotal,
+		lastScanSeconds: lastScanSeconds,
+	}
+}
+
+// Notify increments evax_anomalies_total for n's rule/channel/event ID when n
+// names a rule (a no-anomalies notice names none and is not counted) and
+// bumps evax_last_scan_timestamp_seconds to now.
+func (p *Prometheus) Notify(ctx context.Context, n Notification) error {
+	if n.Rule != "" {
+		p.anomaliesTotal.WithLabelValues(n.Rule, n.Channel, strconv.Itoa(n.EventID)).Inc()
+	}
+	p.lastScanSeconds.Set(float64(time.Now().Unix()))
+	return nil
+}
+
+// Serve starts an HTTP server exposing /metrics on addr and blocks until
+// ctx is canceled or the server fails to start.
+func (p *Prometheus) Serve(ctx context.Context, addr string) error {
+	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(p.registry, promhttp.HandlerOpts{}))
+	server := &http.Server{Addr: addr, Handler: mux}
+
+	errCh := make(chan error, 1)
+	go func() { errCh <- server.ListenAndServe() }()
+
+	select {
+	case <-ctx.Done():
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		return server.Shutdown(shutdownCtx)
+	case err := <-errCh:
+		if err != nil && err != http.ErrServerClosed {
+			return err
+		}
+		return nil
+	}
+}
@@ -0,0 +1,77 @@
+package notifier
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+)
+
+// Slack posts a Block Kit message to an incoming webhook URL.
+type Slack struct {
+	WebhookURL string
+	Client     *http.Client
+}
+
+type slackText struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+type slackBlock struct {
+	Type     string      `json:"type"`
+	Text     *slackText  `json:"text,omitempty"`
+	Elements []slackText `json:"elements,omitempty"`
+}
+
+type slackPayload struct {
+	Blocks []slackBlock `json:"blocks"`
+}
+
+// Notify posts n to s.WebhookURL as a section block with the title/message,
+// plus a context block carrying the rule/severity/channel/event ID.
+func (s Slack) Notify(ctx context.Context, n Notification) error {
+	client := s.Client
+	if client == nil {
+		client = &http.Client{Timeout: 10 * time.Second}
+	}
+
+	payload := slackPayload{
+		Blocks: []slackBlock{
+			{
+				Type: "section",
+				Text: &slackText{Type: "mrkdwn", Text: fmt.Sprintf("*%s*\n%s", n.Title, n.Message)},
+			},
+			{
+				Type: "context",
+				Elements: []slackText{
+					{Type: "mrkdwn", Text: fmt.Sprintf("rule: `%s` | severity: `%s` | channel: `%s` | event id: `%d`", n.Rule, n.Severity, n.Channel, n.EventID)},
+				},
+			},
+		},
+	}
+
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal Slack payload: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, s.WebhookURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to build Slack request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post to Slack: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("Slack webhook returned status %s", resp.Status)
+	}
+	return nil
+}
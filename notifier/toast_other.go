@@ -0,0 +1,24 @@
+//go:build !windows
+
+package notifier
+
+import (
+	"context"
+	"errors"
+)
+
+// errWindowsOnly is returned by Toast.Notify on platforms other than
+// Windows, which have no toast notification system.
+var errWindowsOnly = errors.New("notifier: Windows toast notifications require GOOS=windows")
+
+// Toast sends Windows toast notifications; Evax's original sink. On
+// non-Windows platforms Notify always fails; see toast_windows.go.
+type Toast struct {
+	// AppID defaults to "Windows Security Audit" when empty.
+	AppID string
+}
+
+// Notify always fails on non-Windows platforms; see toast_windows.go.
+func (t Toast) Notify(ctx context.Context, n Notification) error {
+	return errWindowsOnly
+}
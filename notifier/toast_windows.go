@@ -0,0 +1,34 @@
+//go:build windows
+
+package notifier
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/go-toast/toast"
+)
+
+// Toast sends Windows toast notifications; Evax's original sink.
+type Toast struct {
+	// AppID defaults to "Windows Security Audit" when empty.
+	AppID string
+}
+
+// Notify pushes a Windows toast notification with n's title and message.
+func (t Toast) Notify(ctx context.Context, n Notification) error {
+	appID := t.AppID
+	if appID == "" {
+		appID = "Windows Security Audit"
+	}
+
+	notification := toast.Notification{
+		AppID:   appID,
+		Title:   n.Title,
+		Message: n.Message,
+	}
+	if err := notification.Push(); err != nil {
+		return fmt.Errorf("failed to send toast notification: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+//go:build !windows
+
+package positions
+
+import "os"
+
+// replaceFile atomically replaces dst with src; os.Rename is already atomic
+// on POSIX filesystems.
+func replaceFile(src, dst string) error {
+	return os.Rename(src, dst)
+}
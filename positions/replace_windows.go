@@ -0,0 +1,32 @@
+//go:build windows
+
+package positions
+
+import (
+	"os"
+
+	"golang.org/x/sys/windows"
+)
+
+// replaceFile atomically replaces dst with src using MoveFileEx, durably
+// (MOVEFILE_WRITE_THROUGH) across a crash or reboot.
+func replaceFile(src, dst string) error {
+	srcPtr, err := windows.UTF16PtrFromString(src)
+	if err != nil {
+		return err
+	}
+	dstPtr, err := windows.UTF16PtrFromString(dst)
+	if err != nil {
+		return err
+	}
+
+	err = windows.MoveFileEx(srcPtr, dstPtr, windows.MOVEFILE_REPLACE_EXISTING|windows.MOVEFILE_WRITE_THROUGH)
+	if err == nil {
+		return nil
+	}
+
+	// Fall back to remove+rename for filesystems that reject the atomic
+	// replace (e.g. some network shares).
+	_ = os.Remove(dst)
+	return os.Rename(src, dst)
+}
@@ -0,0 +1,123 @@
+// Package positions persists the last-seen EventRecordID per Windows Event
+// Log channel so repeated runs only analyze new events.
+package positions
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Store tracks the last-seen EventRecordID for each channel and persists it
+// to a file on disk. A Store is safe for concurrent use.
+type Store struct {
+	mu   sync.Mutex
+	path string
+	data map[string]uint64
+}
+
+// Load reads the position file at path, returning a ready-to-use Store. A
+// missing or corrupt file (renamed to "<path>.corrupted") starts fresh.
+func Load(path string) (*Store, error) {
+	s := &Store{
+		path: path,
+		data: make(map[string]uint64),
+	}
+
+	raw, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return s, nil
+		}
+		return nil, fmt.Errorf("failed to read positions file: %w", err)
+	}
+
+	if len(raw) == 0 {
+		return s, nil
+	}
+
+	if err := json.Unmarshal(raw, &s.data); err != nil {
+		corrupted := path + ".corrupted"
+		if renameErr := os.Rename(path, corrupted); renameErr != nil {
+			return nil, fmt.Errorf("positions file corrupt (%v) and could not be quarantined: %w", err, renameErr)
+		}
+		s.data = make(map[string]uint64)
+		return s, nil
+	}
+
+	return s, nil
+}
+
+// Get returns the last-seen EventRecordID for channel, or 0 if none is known.
+func (s *Store) Get(channel string) uint64 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.data[channel]
+}
+
+// Put records recordID as the last-seen EventRecordID for channel. It does
+// not write to disk; call Sync (or run SyncLoop) to persist.
+func (s *Store) Put(channel string, recordID uint64) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.data[channel] = recordID
+}
+
+// Sync writes the current positions to disk atomically via a synced temp
+// file and a rename, so a crash mid-write can't leave a truncated file.
+func (s *Store) Sync() error {
+	s.mu.Lock()
+	raw, err := json.MarshalIndent(s.data, "", "  ")
+	s.mu.Unlock()
+	if err != nil {
+		return fmt.Errorf("failed to marshal positions: %w", err)
+	}
+
+	dir := filepath.Dir(s.path)
+	tmp, err := os.CreateTemp(dir, filepath.Base(s.path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("failed to create temp positions file: %w", err)
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // no-op once the rename below succeeds
+
+	if _, err := tmp.Write(raw); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write temp positions file: %w", err)
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to fsync temp positions file: %w", err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to close temp positions file: %w", err)
+	}
+
+	if err := replaceFile(tmpPath, s.path); err != nil {
+		return fmt.Errorf("failed to replace positions file: %w", err)
+	}
+	return nil
+}
+
+// SyncLoop calls Sync every interval until ctx is canceled, then stops
+// without syncing again; callers that need a final flush should call Sync
+// explicitly once any other in-flight work that calls Put has finished.
+func (s *Store) SyncLoop(ctx context.Context, interval time.Duration) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			if err := s.Sync(); err != nil {
+				return err
+			}
+		}
+	}
+}
@@ -0,0 +1,132 @@
+package positions
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+func TestLoadMissingFileStartsFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := s.Get("Security"); got != 0 {
+		t.Errorf("Get on fresh store = %d, want 0", got)
+	}
+}
+
+func TestLoadCorruptFileQuarantinesAndStartsFresh(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+	if err := os.WriteFile(path, []byte("not json"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if got := s.Get("Security"); got != 0 {
+		t.Errorf("Get on quarantined store = %d, want 0", got)
+	}
+	if _, err := os.Stat(path + ".corrupted"); err != nil {
+		t.Errorf("expected corrupt file to be renamed to %s.corrupted: %v", path, err)
+	}
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected original corrupt file to be gone, got err=%v", err)
+	}
+}
+
+func TestSyncPersistsAndReloads(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	s.Put("Security", 42)
+	s.Put("Microsoft-Windows-Sysmon/Operational", 7)
+	if err := s.Sync(); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load after Sync: %v", err)
+	}
+	if got := reloaded.Get("Security"); got != 42 {
+		t.Errorf("Get(Security) = %d, want 42", got)
+	}
+	if got := reloaded.Get("Microsoft-Windows-Sysmon/Operational"); got != 7 {
+		t.Errorf("Get(Sysmon) = %d, want 7", got)
+	}
+}
+
+func TestSyncLoopStopsWithoutSyncingOnCancel(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	s.Put("Security", 99)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() { done <- s.SyncLoop(ctx, time.Hour) }()
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("SyncLoop: %v", err)
+	}
+
+	if _, err := os.Stat(path); !os.IsNotExist(err) {
+		t.Errorf("expected SyncLoop not to write on cancellation, got err=%v", err)
+	}
+
+	if err := s.Sync(); err != nil {
+		t.Fatalf("explicit Sync after SyncLoop stopped: %v", err)
+	}
+	reloaded, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load after explicit Sync: %v", err)
+	}
+	if got := reloaded.Get("Security"); got != 99 {
+		t.Errorf("Get(Security) = %d, want 99", got)
+	}
+}
+
+func TestSyncLoopSyncsPeriodically(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "positions.json")
+
+	s, err := Load(path)
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	s.Put("Security", 7)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	done := make(chan error, 1)
+	go func() { done <- s.SyncLoop(ctx, 10*time.Millisecond) }()
+
+	deadline := time.Now().Add(2 * time.Second)
+	for {
+		if _, err := os.Stat(path); err == nil {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("timed out waiting for SyncLoop's periodic tick to write the positions file")
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+
+	cancel()
+	if err := <-done; err != nil {
+		t.Fatalf("SyncLoop: %v", err)
+	}
+}
@@ -0,0 +1,260 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/zafer1337/Evax/detector"
+	"github.com/zafer1337/Evax/escalator"
+	"github.com/zafer1337/Evax/eventlog"
+	"github.com/zafer1337/Evax/notifier"
+	"github.com/zafer1337/Evax/positions"
+)
+
+// positionsFile is where the last-seen EventRecordID per channel is
+// persisted between runs so already-analyzed events aren't reprocessed.
+const positionsFile = "evax-positions.json"
+
+// Config holds everything Run needs to fetch, analyze, and escalate events,
+// independent of how it was assembled (flags, a config file, tests, ...).
+type Config struct {
+	EventLog        eventlog.Config
+	EventLogs       []eventlog.Config
+	Pipeline        *detector.Pipeline
+	Escalator       escalator.Escalator
+	Notifier        notifier.Notifier
+	PositionsFile   string
+	Daemon          bool
+	Since           time.Duration
+	Workers         int
+	ShutdownTimeout time.Duration
+}
+
+// eventLogConfigs returns every channel/query Run should fetch or
+// subscribe to: cfg.EventLog plus any additional cfg.EventLogs.
+func eventLogConfigs(cfg Config) []eventlog.Config {
+	return append([]eventlog.Config{cfg.EventLog}, cfg.EventLogs...)
+}
+
+// defaultRunConfig returns the Config used when no flags override it: a
+// one-shot scan of Security/4625 and the Sysmon channel, analyzed with the
+// built-in detectors and notified via toast. Escalator is left nil; main
+// fills it in once flags pick a backend.
+func defaultRunConfig() Config {
+	configs := defaultEventLogConfigs()
+	return Config{
+		EventLog:        configs[0],
+		EventLogs:       configs[1:],
+		Pipeline:        detector.NewPipeline(defaultDetectors()...),
+		Notifier:        notifier.Toast{},
+		PositionsFile:   positionsFile,
+		Workers:         4,
+		ShutdownTimeout: 10 * time.Second,
+	}
+}
+
+// Run fetches logs according to cfg, analyzes them for anomalies, and
+// escalates/notifies on whatever it finds. In daemon mode it subscribes and
+// runs until ctx is canceled; otherwise it performs a single pass.
+func Run(ctx context.Context, cfg Config) error {
+	store, err := positions.Load(cfg.PositionsFile)
+	if err != nil {
+		return fmt.Errorf("failed to load positions file: %w", err)
+	}
+
+	if cfg.Daemon {
+		return runDaemon(ctx, cfg, store)
+	}
+	return runOnce(ctx, cfg, store)
+}
+
+// runOnce performs a single fetch/analyze/escalate pass over every channel in
+// eventLogConfigs(cfg). When cfg.Since is set it audits that time window
+// instead of consulting the positions store.
+func runOnce(ctx context.Context, cfg Config, store *positions.Store) error {
+	var logs []eventlog.LogEntry
+	for _, eventCfg := range eventLogConfigs(cfg) {
+		if cfg.Since > 0 {
+			eventCfg.SinceDuration = cfg.Since
+		} else {
+			eventCfg.SinceRecordID = store.Get(eventCfg.EventChannel)
+		}
+
+		channelLogs, err := eventlog.Fetch(eventCfg)
+		if err != nil {
+			return fmt.Errorf("failed to fetch logs for channel %q: %w", eventCfg.EventChannel, err)
+		}
+		if last := eventlog.MaxRecordID(channelLogs); last > 0 {
+			store.Put(eventCfg.EventChannel, last)
+		}
+		logs = append(logs, channelLogs...)
+	}
+	fmt.Printf("Fetched %d logs.\n", len(logs))
+
+	if err := store.Sync(); err != nil {
+		log.Printf("Failed to persist positions file: %v", err)
+	}
+
+	anomalies := analyzeLogs(logs, cfg.Pipeline)
+	if len(anomalies) == 0 {
+		message := "No anomalies detected. Your system is safe."
+		fmt.Println(message)
+		if err := cfg.Notifier.Notify(ctx, notifier.Notification{Title: "Security Audit", Message: message}); err != nil {
+			log.Printf("Failed to send notification: %v", err)
+		}
+		return nil
+	}
+
+	handleAnomalies(ctx, cfg.Escalator, cfg.Notifier, anomalies)
+	return nil
+}
+
+// runDaemon subscribes to every channel in eventLogConfigs(cfg) and funnels
+// matching entries through a worker pool until ctx is canceled, then drains
+// in-flight work (up to cfg.ShutdownTimeout) before returning.
+func runDaemon(ctx context.Context, cfg Config, store *positions.Store) error {
+	channelConfigs := eventLogConfigs(cfg)
+	entries := make(chan eventlog.LogEntry, 256)
+
+	subCtx, cancelSub := context.WithCancel(ctx)
+	defer cancelSub()
+
+	// Each channel gets its own subscription (and its own output channel,
+	// since Subscribe closes it on ctx cancellation); a forwarder per
+	// channel fans them all into the shared entries channel, which is only
+	// closed once every forwarder has drained its subscription.
+	subErrCh := make(chan error, len(channelConfigs))
+	var forwarders sync.WaitGroup
+	for _, eventCfg := range channelConfigs {
+		channelEntries := make(chan eventlog.LogEntry, 256)
+		forwarders.Add(1)
+		go func() {
+			defer forwarders.Done()
+			for entry := range channelEntries {
+				entries <- entry
+			}
+		}()
+		go func() {
+			subErrCh <- eventlog.Subscribe(subCtx, eventCfg, channelEntries)
+		}()
+	}
+	go func() {
+		forwarders.Wait()
+		close(entries)
+	}()
+
+	workers := cfg.Workers
+	if workers <= 0 {
+		workers = 1
+	}
+
+	// Workers escalate and notify under workCtx, which is not canceled with
+	// ctx, so entries drained after shutdown is requested still go out; it is
+	// canceled when runDaemon returns.
+	workCtx, cancelWork := context.WithCancel(context.WithoutCancel(ctx))
+	defer cancelWork()
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for entry := range entries {
+				if findings := cfg.Pipeline.RunOne(entry); len(findings) > 0 {
+					anomalies := make([]Anomaly, len(findings))
+					for i, f := range findings {
+						anomalies[i] = Anomaly{
+							LogID:       f.LogID,
+							Rule:        f.Rule,
+							Severity:    f.Severity,
+							Channel:     f.Channel,
+							EventID:     f.EventID,
+							Description: fmt.Sprintf("[%s/%s] %s", f.Rule, f.Severity, f.Description),
+						}
+					}
+					handleAnomalies(workCtx, cfg.Escalator, cfg.Notifier, anomalies)
+				}
+				if id, err := strconv.ParseUint(entry.ID, 10, 64); err == nil {
+					store.Put(entry.Channel, id)
+				}
+			}
+		}()
+	}
+
+	syncDone := make(chan error, 1)
+	go func() {
+		syncDone <- store.SyncLoop(ctx, 30*time.Second)
+	}()
+
+	<-ctx.Done()
+	log.Println("Shutdown requested, draining in-flight anomalies...")
+	cancelSub()
+
+	drained := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(drained)
+	}()
+
+	select {
+	case <-drained:
+	case <-time.After(cfg.ShutdownTimeout):
+		log.Printf("Shutdown timeout (%s) exceeded; some in-flight events may not have been processed", cfg.ShutdownTimeout)
+	}
+
+	if err := <-syncDone; err != nil {
+		log.Printf("Periodic positions sync failed: %v", err)
+	}
+	if err := store.Sync(); err != nil {
+		log.Printf("Failed to persist positions file: %v", err)
+	}
+	for range channelConfigs {
+		if err := <-subErrCh; err != nil {
+			return fmt.Errorf("subscription error: %w", err)
+		}
+	}
+	return nil
+}
+
+// handleAnomalies escalates a batch of anomalies via esc and delivers each
+// resulting explanation through n. It's shared by the one-shot and daemon
+// paths.
+func handleAnomalies(ctx context.Context, esc escalator.Escalator, n notifier.Notifier, anomalies []Anomaly) {
+	if len(anomalies) == 0 {
+		return
+	}
+
+	byLogID := make(map[string]Anomaly, len(anomalies))
+	input := make([]escalator.Anomaly, len(anomalies))
+	for i, a := range anomalies {
+		fmt.Printf("Anomaly detected: %s\n", a.Description)
+		byLogID[a.LogID] = a
+		input[i] = escalator.Anomaly{LogID: a.LogID, Description: a.Description}
+	}
+
+	explanations, err := esc.Explain(ctx, input)
+	if err != nil {
+		log.Printf("Failed to escalate anomalies: %v", err)
+		return
+	}
+
+	for _, exp := range explanations {
+		a := byLogID[exp.LogID]
+		notification := notifier.Notification{
+			Title:    "Security Audit - Anomaly Detected",
+			Message:  exp.Summary,
+			LogID:    a.LogID,
+			Rule:     a.Rule,
+			Severity: a.Severity,
+			Channel:  a.Channel,
+			EventID:  a.EventID,
+		}
+		if err := n.Notify(ctx, notification); err != nil {
+			log.Printf("Failed to send notification for anomaly %s: %v", exp.LogID, err)
+		}
+	}
+}